@@ -0,0 +1,118 @@
+// Package v1 is a hand-maintained, wire-compatible stand-in for the
+// protoc-gen-go output of blob.proto in this package. It implements the
+// plain protobuf wire format (varint and length-delimited fields) directly,
+// since this trimmed tree has no protoc/protoc-gen-go available to
+// regenerate it from the .proto source.
+package v1
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// BlobProto is the wire representation of share.Blob.
+type BlobProto struct {
+	NamespaceId      []byte `json:"namespace_id,omitempty"`
+	NamespaceVersion uint32 `json:"namespace_version,omitempty"`
+	ShareVersion     uint32 `json:"share_version,omitempty"`
+	Data             []byte `json:"data,omitempty"`
+	Signer           []byte `json:"signer,omitempty"`
+	ContentType      uint32 `json:"content_type,omitempty"`
+}
+
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+// Marshal encodes pb using the standard protobuf wire format.
+func (pb *BlobProto) Marshal() ([]byte, error) {
+	var out []byte
+	out = appendBytesField(out, 1, pb.NamespaceId)
+	out = appendVarintField(out, 2, uint64(pb.NamespaceVersion))
+	out = appendVarintField(out, 3, uint64(pb.ShareVersion))
+	out = appendBytesField(out, 4, pb.Data)
+	out = appendBytesField(out, 5, pb.Signer)
+	out = appendVarintField(out, 6, uint64(pb.ContentType))
+	return out, nil
+}
+
+// Unmarshal decodes b, which must be in the standard protobuf wire format,
+// into pb. Unknown fields are skipped.
+func (pb *BlobProto) Unmarshal(b []byte) error {
+	for len(b) > 0 {
+		field, wireType, n := decodeTag(b)
+		if n <= 0 {
+			return fmt.Errorf("invalid proto tag")
+		}
+		b = b[n:]
+
+		switch wireType {
+		case wireVarint:
+			v, n := binary.Uvarint(b)
+			if n <= 0 {
+				return fmt.Errorf("invalid varint for field %d", field)
+			}
+			b = b[n:]
+			switch field {
+			case 2:
+				pb.NamespaceVersion = uint32(v)
+			case 3:
+				pb.ShareVersion = uint32(v)
+			case 6:
+				pb.ContentType = uint32(v)
+			}
+		case wireBytes:
+			length, n := binary.Uvarint(b)
+			if n <= 0 {
+				return fmt.Errorf("invalid length for field %d", field)
+			}
+			b = b[n:]
+			if uint64(len(b)) < length {
+				return fmt.Errorf("truncated field %d", field)
+			}
+			value := append([]byte{}, b[:length]...)
+			b = b[length:]
+			switch field {
+			case 1:
+				pb.NamespaceId = value
+			case 4:
+				pb.Data = value
+			case 5:
+				pb.Signer = value
+			}
+		default:
+			return fmt.Errorf("unsupported wire type %d for field %d", wireType, field)
+		}
+	}
+	return nil
+}
+
+func decodeTag(b []byte) (field, wireType, n int) {
+	v, n := binary.Uvarint(b)
+	if n <= 0 {
+		return 0, 0, n
+	}
+	return int(v >> 3), int(v & 0x7), n
+}
+
+func appendVarintField(out []byte, field int, v uint64) []byte {
+	if v == 0 {
+		return out
+	}
+	out = appendTag(out, field, wireVarint)
+	return binary.AppendUvarint(out, v)
+}
+
+func appendBytesField(out []byte, field int, v []byte) []byte {
+	if len(v) == 0 {
+		return out
+	}
+	out = appendTag(out, field, wireBytes)
+	out = binary.AppendUvarint(out, uint64(len(v)))
+	return append(out, v...)
+}
+
+func appendTag(out []byte, field, wireType int) []byte {
+	return binary.AppendUvarint(out, uint64(field)<<3|uint64(wireType))
+}
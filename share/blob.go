@@ -7,7 +7,6 @@ import (
 	"sort"
 
 	v1 "github.com/celestiaorg/go-square/v2/proto/blob/v1"
-	"google.golang.org/protobuf/proto"
 )
 
 // Blob (stands for binary large object) is a core type that represents data
@@ -18,11 +17,37 @@ type Blob struct {
 	data         []byte
 	shareVersion uint8
 	signer       []byte
+	contentType  uint8
 }
 
+// ShareVersionTwo is a share version that, in addition to an optional
+// signer, carries a content type tag describing how a blob's data should
+// be interpreted. See Blob.ContentType.
+const ShareVersionTwo = uint8(2)
+
+// Content type tags carried by a share version 2 blob, identifying how its
+// data should be interpreted.
+const (
+	ContentTypeRaw uint8 = iota
+	ContentTypeProtobuf
+	ContentTypeCBOR
+	ContentTypeGzipRaw
+)
+
+// MaxShareVersion is the highest share version NewBlobFromProto will
+// accept.
+const MaxShareVersion = ShareVersionTwo
+
 // New creates a new coretypes.Blob from the provided data after performing
 // basic stateless checks over it.
 func NewBlob(ns Namespace, data []byte, shareVersion uint8, signer []byte) (*Blob, error) {
+	return newBlob(ns, data, shareVersion, signer, ContentTypeRaw)
+}
+
+// newBlob is the shared constructor behind NewBlob and NewV2Blob. It is
+// kept unexported so that adding share-version-2's contentType field
+// doesn't force a breaking change onto NewBlob's signature.
+func newBlob(ns Namespace, data []byte, shareVersion uint8, signer []byte, contentType uint8) (*Blob, error) {
 	if len(data) == 0 {
 		return nil, errors.New("data can not be empty")
 	}
@@ -37,37 +62,58 @@ func NewBlob(ns Namespace, data []byte, shareVersion uint8, signer []byte) (*Blo
 		if signer != nil {
 			return nil, errors.New("share version 0 does not support signer")
 		}
+		if contentType != ContentTypeRaw {
+			return nil, errors.New("share version 0 does not support a content type")
+		}
 	case ShareVersionOne:
 		if len(signer) != SignerSize {
 			return nil, fmt.Errorf("share version 1 requires signer of size %d bytes", SignerSize)
 		}
+		if contentType != ContentTypeRaw {
+			return nil, errors.New("share version 1 does not support a content type")
+		}
+	case ShareVersionTwo:
+		if signer != nil && len(signer) != SignerSize {
+			return nil, fmt.Errorf("share version 2 signer must be omitted or %d bytes", SignerSize)
+		}
+		if contentType > ContentTypeGzipRaw {
+			return nil, fmt.Errorf("content type %d not supported", contentType)
+		}
 	// Note that we don't specifically check that shareVersion is less than 128 as this is caught
 	// by the default case
 	default:
-		return nil, fmt.Errorf("share version %d not supported. Please use 0 or 1", shareVersion)
+		return nil, fmt.Errorf("share version %d not supported. Please use 0, 1 or 2", shareVersion)
 	}
 	return &Blob{
 		namespace:    ns,
 		data:         data,
 		shareVersion: shareVersion,
 		signer:       signer,
+		contentType:  contentType,
 	}, nil
 }
 
 // NewV0Blob creates a new blob with share version 0
 func NewV0Blob(ns Namespace, data []byte) (*Blob, error) {
-	return NewBlob(ns, data, 0, nil)
+	return NewBlob(ns, data, ShareVersionZero, nil)
 }
 
 // NewV1Blob creates a new blob with share version 1
 func NewV1Blob(ns Namespace, data []byte, signer []byte) (*Blob, error) {
-	return NewBlob(ns, data, 1, signer)
+	return NewBlob(ns, data, ShareVersionOne, signer)
+}
+
+// NewV2Blob creates a new blob with share version 2, tagging its data with
+// contentType. signer may be nil, since share version 2 carries it as an
+// optional field.
+func NewV2Blob(ns Namespace, data []byte, contentType uint8, signer []byte) (*Blob, error) {
+	return newBlob(ns, data, ShareVersionTwo, signer, contentType)
 }
 
 // UnmarshalBlob unmarshals a blob from the proto encoded bytes
 func UnmarshalBlob(blob []byte) (*Blob, error) {
 	pb := &v1.BlobProto{}
-	err := proto.Unmarshal(blob, pb)
+	err := pb.Unmarshal(blob)
 	if err != nil {
 		return nil, fmt.Errorf("failed to unmarshal blob: %w", err)
 	}
@@ -82,8 +128,9 @@ func (b *Blob) Marshal() ([]byte, error) {
 		ShareVersion:     uint32(b.shareVersion),
 		Data:             b.data,
 		Signer:           b.signer,
+		ContentType:      uint32(b.contentType),
 	}
-	return proto.Marshal(pb)
+	return pb.Marshal()
 }
 
 // MarshalJSON converts blob's data to the json encoded bytes
@@ -94,6 +141,7 @@ func (b *Blob) MarshalJSON() ([]byte, error) {
 		ShareVersion:     uint32(b.shareVersion),
 		Data:             b.data,
 		Signer:           b.signer,
+		ContentType:      uint32(b.contentType),
 	}
 	return json.Marshal(pb)
 }
@@ -123,15 +171,19 @@ func NewBlobFromProto(pb *v1.BlobProto) (*Blob, error) {
 	if pb.ShareVersion > MaxShareVersion {
 		return nil, fmt.Errorf("share version can not be greater than MaxShareVersion %d", MaxShareVersion)
 	}
+	if pb.ContentType > uint32(ContentTypeGzipRaw) {
+		return nil, fmt.Errorf("content type %d not supported", pb.ContentType)
+	}
 	ns, err := NewNamespace(uint8(pb.NamespaceVersion), pb.NamespaceId)
 	if err != nil {
 		return nil, fmt.Errorf("invalid namespace: %w", err)
 	}
-	return NewBlob(
+	return newBlob(
 		ns,
 		pb.Data,
 		uint8(pb.ShareVersion),
 		pb.Signer,
+		uint8(pb.ContentType),
 	)
 }
 
@@ -150,6 +202,12 @@ func (b *Blob) Signer() []byte {
 	return b.signer
 }
 
+// ContentType returns the content type tag of a share version 2 blob. It
+// is always ContentTypeRaw for share versions 0 and 1.
+func (b *Blob) ContentType() uint8 {
+	return b.contentType
+}
+
 // Data returns the data of the blob
 func (b *Blob) Data() []byte {
 	return b.data
@@ -0,0 +1,166 @@
+package share
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// BlobSource supplies shares to a BlobIterator as they become available.
+// Implementations may back this with a light client's share retrieval, a
+// rollup sequencer's local mempool, or any other incremental share feed.
+// NextShares should return io.EOF once no further shares will ever be
+// produced.
+type BlobSource interface {
+	// NextShares returns the next batch of shares to process. It may block
+	// until shares are available, and should return ctx.Err() if ctx is
+	// cancelled first.
+	NextShares(ctx context.Context) ([]Share, error)
+}
+
+// BlobIterator reassembles blobs for a single namespace from the shares
+// produced by a BlobSource, one blob at a time, so that a caller can
+// process a namespace's blobs as they arrive instead of buffering an
+// entire square.
+type BlobIterator struct {
+	src       BlobSource
+	namespace Namespace
+
+	buffered []Share
+}
+
+// NewBlobIterator creates a BlobIterator that reassembles blobs addressed
+// to ns from the shares produced by src.
+func NewBlobIterator(src BlobSource, ns Namespace) *BlobIterator {
+	return &BlobIterator{src: src, namespace: ns}
+}
+
+// Next returns the next blob addressed to the iterator's namespace,
+// pulling further shares from the underlying BlobSource as needed. It
+// returns io.EOF once the source is exhausted.
+func (it *BlobIterator) Next(ctx context.Context) (*Blob, error) {
+	for {
+		sh, err := it.nextMatchingShare(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		infoByte, err := sh.InfoByte()
+		if err != nil {
+			return nil, err
+		}
+		if !infoByte.IsSequenceStart() {
+			// an orphaned continuation share without its sequence start
+			// (e.g. the iterator started mid-stream); skip it.
+			continue
+		}
+
+		seqLen, err := sh.SequenceLen()
+		if err != nil {
+			return nil, err
+		}
+		shareVersion := infoByte.Version()
+
+		raw := sh.ToBytes()
+		cursor := NamespaceSize + ShareInfoBytes + SequenceLenBytes
+
+		contentType := uint8(ContentTypeRaw)
+		if shareVersion == ShareVersionTwo {
+			contentType = raw[cursor]
+			cursor++
+		}
+
+		var signer []byte
+		switch shareVersion {
+		case ShareVersionOne:
+			signer = append([]byte{}, raw[cursor:cursor+SignerSize]...)
+			cursor += SignerSize
+		case ShareVersionTwo:
+			signerSlot := raw[cursor : cursor+SignerSize]
+			cursor += SignerSize
+			if !isZero(signerSlot) {
+				signer = append([]byte{}, signerSlot...)
+			}
+		}
+
+		data := append([]byte{}, raw[cursor:]...)
+		for uint32(len(data)) < seqLen {
+			next, err := it.nextMatchingShare(ctx)
+			if err != nil {
+				return nil, err
+			}
+			nextInfoByte, err := next.InfoByte()
+			if err != nil {
+				return nil, err
+			}
+			if nextInfoByte.IsSequenceStart() {
+				return nil, errors.New("share: blob sequence ended prematurely")
+			}
+			nraw := next.ToBytes()
+			data = append(data, nraw[NamespaceSize+ShareInfoBytes:]...)
+		}
+		data = data[:seqLen]
+
+		if shareVersion == ShareVersionTwo {
+			return NewV2Blob(it.namespace, data, contentType, signer)
+		}
+		return NewBlob(it.namespace, data, shareVersion, signer)
+	}
+}
+
+// nextMatchingShare pops the next share belonging to the iterator's
+// namespace, pulling more shares from the source as needed, and discarding
+// shares addressed to other namespaces along the way.
+func (it *BlobIterator) nextMatchingShare(ctx context.Context) (*Share, error) {
+	for {
+		for len(it.buffered) > 0 {
+			sh := it.buffered[0]
+			it.buffered = it.buffered[1:]
+			ns, err := sh.Namespace()
+			if err != nil {
+				return nil, err
+			}
+			if ns.Compare(it.namespace) == 0 {
+				return &sh, nil
+			}
+		}
+
+		shares, err := it.src.NextShares(ctx)
+		if err != nil {
+			return nil, err
+		}
+		it.buffered = append(it.buffered, shares...)
+	}
+}
+
+// SubscribeBlobs is a convenience wrapper around BlobIterator that streams
+// blobs addressed to ns over a channel. The returned blob channel is
+// closed once src is exhausted or ctx is done; the error channel receives
+// at most one error (other than io.EOF, which ends iteration silently)
+// before it too is closed.
+func SubscribeBlobs(ctx context.Context, src BlobSource, ns Namespace) (<-chan *Blob, <-chan error) {
+	blobCh := make(chan *Blob)
+	errCh := make(chan error, 1)
+
+	it := NewBlobIterator(src, ns)
+	go func() {
+		defer close(blobCh)
+		defer close(errCh)
+		for {
+			blob, err := it.Next(ctx)
+			if err != nil {
+				if !errors.Is(err, io.EOF) {
+					errCh <- err
+				}
+				return
+			}
+			select {
+			case blobCh <- blob:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return blobCh, errCh
+}
@@ -0,0 +1,113 @@
+package share
+
+import (
+	"bytes"
+	"context"
+	"testing"
+)
+
+func TestBlobIteratorMultipleShares(t *testing.T) {
+	ns := namespaceWithID(t, 9)
+	blob, err := NewV0Blob(ns, bytes.Repeat([]byte{0x11}, 3*ShareSize))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	shares, err := blob.ToShares()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(shares) < 2 {
+		t.Fatalf("expected the blob to span multiple shares, got %d", len(shares))
+	}
+
+	src := &staticBlobSource{shares: shares}
+	it := NewBlobIterator(src, ns)
+
+	got, err := it.Next(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(got.Data(), blob.Data()) {
+		t.Fatal("expected data to round trip unchanged across multiple shares")
+	}
+
+	if _, err := it.Next(context.Background()); err == nil {
+		t.Fatal("expected io.EOF once the source is exhausted")
+	}
+}
+
+func TestBlobIteratorSkipsOtherNamespaces(t *testing.T) {
+	wantNS := namespaceWithID(t, 12)
+	otherNS := namespaceWithID(t, 1)
+
+	other, err := NewV0Blob(otherNS, []byte("not for you"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	otherShares, err := other.ToShares()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want, err := NewV0Blob(wantNS, []byte("for you"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	wantShares, err := want.ToShares()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	src := &staticBlobSource{shares: append(otherShares, wantShares...)}
+	it := NewBlobIterator(src, wantNS)
+
+	got, err := it.Next(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(got.Data(), want.Data()) {
+		t.Fatal("expected the iterator to surface only the matching namespace's blob")
+	}
+}
+
+func TestSubscribeBlobs(t *testing.T) {
+	ns := namespaceWithID(t, 13)
+	first, err := NewV0Blob(ns, []byte("first"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := NewV0Blob(ns, []byte("second"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	firstShares, err := first.ToShares()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	secondShares, err := second.ToShares()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	src := &staticBlobSource{shares: append(firstShares, secondShares...)}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	blobCh, errCh := SubscribeBlobs(ctx, src, ns)
+
+	var got []*Blob
+	for blob := range blobCh {
+		got = append(got, blob)
+	}
+	if err, ok := <-errCh; ok {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != 2 {
+		t.Fatalf("expected 2 blobs, got %d", len(got))
+	}
+	if !bytes.Equal(got[0].Data(), first.Data()) || !bytes.Equal(got[1].Data(), second.Data()) {
+		t.Fatal("expected blobs to be delivered in order with their data intact")
+	}
+}
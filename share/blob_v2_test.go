@@ -0,0 +1,165 @@
+package share
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"testing"
+)
+
+// staticBlobSource is a BlobSource backed by a fixed, already-available
+// slice of shares, useful for exercising BlobIterator in tests without a
+// real share-retrieval backend.
+type staticBlobSource struct {
+	shares []Share
+	served bool
+}
+
+func (s *staticBlobSource) NextShares(ctx context.Context) ([]Share, error) {
+	if s.served {
+		return nil, io.EOF
+	}
+	s.served = true
+	return s.shares, nil
+}
+
+func TestNewV2Blob(t *testing.T) {
+	ns := namespaceWithID(t, 3)
+	signer := bytes.Repeat([]byte{7}, SignerSize)
+
+	tests := []struct {
+		name        string
+		contentType uint8
+		signer      []byte
+		wantErr     bool
+	}{
+		{name: "raw content type, no signer", contentType: ContentTypeRaw, signer: nil},
+		{name: "protobuf content type with signer", contentType: ContentTypeProtobuf, signer: signer},
+		{name: "cbor content type, no signer", contentType: ContentTypeCBOR, signer: nil},
+		{name: "gzip'd raw content type", contentType: ContentTypeGzipRaw, signer: nil},
+		{name: "unknown content type", contentType: ContentTypeGzipRaw + 1, wantErr: true},
+		{name: "bad signer size", contentType: ContentTypeRaw, signer: []byte{1, 2, 3}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blob, err := NewV2Blob(ns, []byte("hello celestia"), tt.contentType, tt.signer)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if blob.ShareVersion() != ShareVersionTwo {
+				t.Fatalf("expected share version %d, got %d", ShareVersionTwo, blob.ShareVersion())
+			}
+			if blob.ContentType() != tt.contentType {
+				t.Fatalf("expected content type %d, got %d", tt.contentType, blob.ContentType())
+			}
+		})
+	}
+}
+
+// TestV2BlobMarshalRoundTrip checks that the proto (and proto-backed JSON)
+// encoding round trips a v2 blob's share version, data, signer, and content
+// type.
+func TestV2BlobMarshalRoundTrip(t *testing.T) {
+	ns := namespaceWithID(t, 4)
+	signer := bytes.Repeat([]byte{5}, SignerSize)
+
+	blob, err := NewV2Blob(ns, []byte("round trip me"), ContentTypeProtobuf, signer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	marshalled, err := blob.Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := UnmarshalBlob(marshalled)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got.ShareVersion() != blob.ShareVersion() {
+		t.Fatalf("expected share version %d, got %d", blob.ShareVersion(), got.ShareVersion())
+	}
+	if !bytes.Equal(got.Data(), blob.Data()) {
+		t.Fatal("expected data to round trip unchanged")
+	}
+	if !bytes.Equal(got.Signer(), blob.Signer()) {
+		t.Fatal("expected signer to round trip unchanged")
+	}
+	if got.ContentType() != blob.ContentType() {
+		t.Fatalf("expected content type %d, got %d", blob.ContentType(), got.ContentType())
+	}
+
+	asJSON, err := blob.MarshalJSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var fromJSON Blob
+	if err := fromJSON.UnmarshalJSON(asJSON); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if fromJSON.ShareVersion() != blob.ShareVersion() {
+		t.Fatalf("expected share version %d, got %d", blob.ShareVersion(), fromJSON.ShareVersion())
+	}
+	if fromJSON.ContentType() != blob.ContentType() {
+		t.Fatalf("expected content type %d, got %d", blob.ContentType(), fromJSON.ContentType())
+	}
+}
+
+// TestV2BlobShareRoundTrip checks that ContentType also survives a
+// share-level round trip (ToShares followed by reassembly via a
+// BlobIterator).
+func TestV2BlobShareRoundTrip(t *testing.T) {
+	ns := namespaceWithID(t, 6)
+	signer := bytes.Repeat([]byte{9}, SignerSize)
+
+	tests := []struct {
+		name   string
+		signer []byte
+	}{
+		{name: "with signer", signer: signer},
+		{name: "without signer", signer: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blob, err := NewV2Blob(ns, []byte("share me twice over to span shares"), ContentTypeCBOR, tt.signer)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			shares, err := blob.ToShares()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			src := &staticBlobSource{shares: shares}
+			it := NewBlobIterator(src, ns)
+			got, err := it.Next(context.Background())
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got.ShareVersion() != blob.ShareVersion() {
+				t.Fatalf("expected share version %d, got %d", blob.ShareVersion(), got.ShareVersion())
+			}
+			if got.ContentType() != blob.ContentType() {
+				t.Fatalf("expected content type %d, got %d", blob.ContentType(), got.ContentType())
+			}
+			if !bytes.Equal(got.Data(), blob.Data()) {
+				t.Fatal("expected data to round trip unchanged")
+			}
+			if !bytes.Equal(got.Signer(), blob.Signer()) {
+				t.Fatalf("expected signer %x, got %x", blob.Signer(), got.Signer())
+			}
+		})
+	}
+}
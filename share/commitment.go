@@ -0,0 +1,208 @@
+package share
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+)
+
+// SubtreeRootThreshold determines the width of the subtrees used to build a
+// blob's share commitment: subtreeWidth divides the blob's share count by
+// this threshold, rounding up, and rounds the result up to a power of two,
+// so blobs of more than SubtreeRootThreshold shares get wider subtrees.
+// Subtree sizes are powers of two no larger than that width, so that they
+// align with the subtree roots an NMT produces for a row of the square.
+// See the blob share commitment rules in the Celestia specs for
+// background: https://github.com/celestiaorg/celestia-app/blob/main/specs/src/specs/data_square_layout.md
+const SubtreeRootThreshold = 64
+
+// Commitment is a commitment to a blob. It is the Merkle root of the
+// subtree roots of the shares that the blob was split into, and is used by
+// the Celestia network to identify a blob without needing the blob's full
+// data.
+type Commitment []byte
+
+// Equal returns true if the two commitments are equal.
+func (com Commitment) Equal(other Commitment) bool {
+	return bytes.Equal(com, other)
+}
+
+// String returns the hex encoded commitment.
+func (com Commitment) String() string {
+	return hex.EncodeToString(com)
+}
+
+// Commitment calculates the share commitment of the blob, as described in
+// CreateCommitment.
+func (b *Blob) Commitment() (Commitment, error) {
+	return CreateCommitment(b)
+}
+
+// CreateCommitment generates the share commitment for a blob. To generate
+// the commitment, the blob is split into shares, the shares are split into
+// subtrees of a size determined by SubtreeRootThreshold, the subtree roots
+// are computed using an NMT, and finally the subtree roots are used as
+// leaves to compute a plain Merkle root. This is the same algorithm used by
+// the Celestia network to commit to a blob's data and namespace ahead of
+// inclusion in a square.
+func CreateCommitment(blob *Blob) (Commitment, error) {
+	if blob == nil {
+		return nil, errors.New("cannot create commitment for nil blob")
+	}
+
+	shares, err := blob.ToShares()
+	if err != nil {
+		return nil, err
+	}
+
+	subTreeRoots, err := subtreeRoots(blob.Namespace(), shares, SubtreeRootThreshold)
+	if err != nil {
+		return nil, err
+	}
+
+	return merkleRoot(subTreeRoots), nil
+}
+
+// subtreeRoots splits shares into groups sized according to the
+// subtreeWidth rule and returns the NMT subtree root of each group.
+func subtreeRoots(ns Namespace, shares []Share, subtreeRootThreshold int) ([][]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("cannot compute subtree roots of zero shares")
+	}
+
+	treeSizes := merkleMountainRangeSizes(uint64(len(shares)), uint64(subtreeWidth(len(shares), subtreeRootThreshold)))
+	roots := make([][]byte, 0, len(treeSizes))
+	cursor := uint64(0)
+	for _, size := range treeSizes {
+		root, err := nmtSubtreeRoot(ns, shares[cursor:cursor+size])
+		if err != nil {
+			return nil, err
+		}
+		roots = append(roots, root)
+		cursor += size
+	}
+
+	return roots, nil
+}
+
+// subtreeWidth determines the maximum number of leaves in a subtree used
+// while computing a blob's share commitment. shareSequenceLength is the
+// number of shares the blob was split into. See the blob share commitment
+// rules spec for the full rationale behind this rule.
+func subtreeWidth(shareSequenceLength, subtreeRootThreshold int) int {
+	s := shareSequenceLength / subtreeRootThreshold
+	if shareSequenceLength%subtreeRootThreshold != 0 {
+		s++
+	}
+	width := roundUpPowerOfTwo(s)
+	if width > shareSequenceLength {
+		return roundUpPowerOfTwo(shareSequenceLength)
+	}
+	return width
+}
+
+// merkleMountainRangeSizes splits totalSize leaves into a sequence of
+// subtree sizes, each a power of two no larger than maxSize, following the
+// merkle mountain range construction used for NMT subtree roots.
+func merkleMountainRangeSizes(totalSize, maxSize uint64) []uint64 {
+	var sizes []uint64
+	for totalSize != 0 {
+		switch {
+		case maxSize == 0:
+			sizes = append(sizes, totalSize)
+			totalSize = 0
+		case totalSize >= maxSize:
+			sizes = append(sizes, maxSize)
+			totalSize -= maxSize
+		default:
+			size := roundDownPowerOfTwo(totalSize)
+			sizes = append(sizes, size)
+			totalSize -= size
+		}
+	}
+	return sizes
+}
+
+func roundUpPowerOfTwo(v int) int {
+	if v <= 1 {
+		return 1
+	}
+	p := 1
+	for p < v {
+		p <<= 1
+	}
+	return p
+}
+
+func roundDownPowerOfTwo(v uint64) uint64 {
+	p := uint64(1)
+	for p<<1 <= v {
+		p <<= 1
+	}
+	return p
+}
+
+// nmtNode is a node of a namespaced Merkle tree: it carries the minimum and
+// maximum namespace covered by the subtree it roots, alongside the digest
+// of its contents, following the NMT leaf/node hashing rules.
+type nmtNode struct {
+	min, max []byte
+	hash     []byte
+}
+
+func (n nmtNode) bytes() []byte {
+	out := make([]byte, 0, len(n.min)+len(n.max)+len(n.hash))
+	out = append(out, n.min...)
+	out = append(out, n.max...)
+	out = append(out, n.hash...)
+	return out
+}
+
+// nmtSubtreeRoot computes the namespaced Merkle tree root, including its
+// bubbled up min/max namespace, over a contiguous run of shares that
+// belong to a single namespace. The number of shares is expected to be a
+// power of two, as guaranteed by merkleMountainRangeSizes.
+func nmtSubtreeRoot(ns Namespace, shares []Share) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New("cannot compute an nmt subtree root of zero shares")
+	}
+
+	nodes := make([]nmtNode, len(shares))
+	for i, sh := range shares {
+		nodes[i] = nmtLeaf(ns, sh.ToBytes())
+	}
+
+	for len(nodes) > 1 {
+		next := make([]nmtNode, 0, (len(nodes)+1)/2)
+		for i := 0; i < len(nodes); i += 2 {
+			if i+1 == len(nodes) {
+				next = append(next, nodes[i])
+				continue
+			}
+			next = append(next, nmtParent(nodes[i], nodes[i+1]))
+		}
+		nodes = next
+	}
+
+	return nodes[0].bytes(), nil
+}
+
+// nmtLeaf computes an NMT leaf hash over data, which is expected to already
+// be namespace-prefixed (e.g. a share's raw bytes) per the NMT leaf hashing
+// rule H(0x00 || namespacedData).
+func nmtLeaf(ns Namespace, data []byte) nmtNode {
+	h := sha256.New()
+	h.Write([]byte{0}) // NMT leaf domain separator
+	h.Write(data)
+	nsBytes := ns.Bytes()
+	return nmtNode{min: nsBytes, max: nsBytes, hash: h.Sum(nil)}
+}
+
+func nmtParent(left, right nmtNode) nmtNode {
+	h := sha256.New()
+	h.Write([]byte{1}) // NMT inner node domain separator
+	h.Write(left.bytes())
+	h.Write(right.bytes())
+	return nmtNode{min: left.min, max: right.max, hash: h.Sum(nil)}
+}
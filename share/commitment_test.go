@@ -0,0 +1,162 @@
+package share
+
+import (
+	"bytes"
+	"testing"
+)
+
+// namespaceWithID builds a valid version-0 namespace whose id ends with the
+// given byte, to make test namespaces easy to tell apart.
+func namespaceWithID(t *testing.T, id byte) Namespace {
+	t.Helper()
+	raw := make([]byte, NamespaceIDSize)
+	raw[len(raw)-1] = id
+	ns, err := NewNamespace(NamespaceVersionZero, raw)
+	if err != nil {
+		t.Fatalf("failed to create namespace: %v", err)
+	}
+	return ns
+}
+
+func TestSubtreeWidth(t *testing.T) {
+	tests := []struct {
+		shareSequenceLength  int
+		subtreeRootThreshold int
+		want                 int
+	}{
+		{shareSequenceLength: 1, subtreeRootThreshold: 64, want: 1},
+		{shareSequenceLength: 64, subtreeRootThreshold: 64, want: 1},
+		{shareSequenceLength: 65, subtreeRootThreshold: 64, want: 2},
+		{shareSequenceLength: 128, subtreeRootThreshold: 64, want: 2},
+		{shareSequenceLength: 256, subtreeRootThreshold: 64, want: 4},
+	}
+	for _, tt := range tests {
+		got := subtreeWidth(tt.shareSequenceLength, tt.subtreeRootThreshold)
+		if got != tt.want {
+			t.Errorf("subtreeWidth(%d, %d) = %d, want %d", tt.shareSequenceLength, tt.subtreeRootThreshold, got, tt.want)
+		}
+	}
+}
+
+func TestMerkleMountainRangeSizes(t *testing.T) {
+	tests := []struct {
+		totalSize uint64
+		maxSize   uint64
+		want      []uint64
+	}{
+		{totalSize: 1, maxSize: 1, want: []uint64{1}},
+		{totalSize: 2, maxSize: 2, want: []uint64{2}},
+		{totalSize: 3, maxSize: 2, want: []uint64{2, 1}},
+		{totalSize: 11, maxSize: 4, want: []uint64{4, 4, 2, 1}},
+	}
+	for _, tt := range tests {
+		got := merkleMountainRangeSizes(tt.totalSize, tt.maxSize)
+		if len(got) != len(tt.want) {
+			t.Fatalf("merkleMountainRangeSizes(%d, %d) = %v, want %v", tt.totalSize, tt.maxSize, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("merkleMountainRangeSizes(%d, %d) = %v, want %v", tt.totalSize, tt.maxSize, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestCreateCommitment(t *testing.T) {
+	ns := namespaceWithID(t, 1)
+	blob, err := NewV0Blob(ns, bytes.Repeat([]byte{1}, 500))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	commitment, err := CreateCommitment(blob)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(commitment) != 32 {
+		t.Fatalf("expected a 32 byte commitment, got %d bytes", len(commitment))
+	}
+
+	// the commitment method on Blob should produce the same commitment.
+	fromMethod, err := blob.Commitment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !commitment.Equal(fromMethod) {
+		t.Fatal("Blob.Commitment() disagrees with CreateCommitment()")
+	}
+
+	// commitments must be deterministic.
+	again, err := CreateCommitment(blob)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !commitment.Equal(again) {
+		t.Fatal("commitment is not deterministic")
+	}
+
+	// a different namespace must produce a different commitment.
+	otherNs := namespaceWithID(t, 2)
+	otherBlob, err := NewV0Blob(otherNs, bytes.Repeat([]byte{1}, 500))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	otherCommitment, err := CreateCommitment(otherBlob)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if commitment.Equal(otherCommitment) {
+		t.Fatal("expected different namespaces to produce different commitments")
+	}
+
+	// a v1 blob with a signer must still produce a valid commitment.
+	signer := bytes.Repeat([]byte{9}, SignerSize)
+	v1Blob, err := NewV1Blob(ns, bytes.Repeat([]byte{1}, 500), signer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	v1Commitment, err := CreateCommitment(v1Blob)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(v1Commitment) != 32 {
+		t.Fatalf("expected a 32 byte commitment, got %d bytes", len(v1Commitment))
+	}
+	if commitment.Equal(v1Commitment) {
+		t.Fatal("expected the signer to change the resulting commitment")
+	}
+}
+
+func TestCreateCommitmentNilBlob(t *testing.T) {
+	if _, err := CreateCommitment(nil); err == nil {
+		t.Fatal("expected an error when creating a commitment for a nil blob")
+	}
+}
+
+// TestCreateCommitmentKnownAnswer pins CreateCommitment to a commitment
+// computed independently of this package, by applying the documented
+// hashing rules (namespaced leaf/inner NMT hashing, subtree chunking, plain
+// Merkle root) in a separate script rather than calling CreateCommitment
+// itself. That guards against a regression in the hashing rules (e.g.
+// double-hashing the namespace in an NMT leaf), which would otherwise still
+// produce a plausible-looking 32 byte, deterministic, namespace-sensitive
+// commitment; it does not, on its own, prove byte-for-byte compatibility
+// with celestia-app's CreateCommitment, which this package was not checked
+// against.
+func TestCreateCommitmentKnownAnswer(t *testing.T) {
+	ns := namespaceWithID(t, 1)
+	blob, err := NewV0Blob(ns, bytes.Repeat([]byte{0xAB}, 10))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	commitment, err := CreateCommitment(blob)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "d2a5a7c7b5686513b19b81dd08e80836e8f741492e0d06ae8fa0eda0d8674718"
+	if commitment.String() != want {
+		t.Fatalf("commitment = %s, want %s", commitment.String(), want)
+	}
+}
@@ -0,0 +1,369 @@
+package share
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// reservedBytesSize is the number of bytes each compact share reserves to
+// record where, within that share's own data portion, a new blob begins -
+// a simplified, locally-consistent reserved-bytes scheme inspired by
+// celestia-app's compact share format, though not byte-identical to its
+// wire layout. ParseCompactShares cross-checks these offsets against the
+// blob boundaries it reconstructs from the varint delimiters, so a
+// corrupted reserved-bytes field is caught rather than silently ignored.
+const reservedBytesSize = 4
+
+// firstCompactShareDataSize and contCompactShareDataSize are the numbers
+// of bytes of a share's fixed ShareSize bytes left over for framed blob
+// data once the namespace, info byte, reserved bytes and (for the first
+// share only) sequence length have been written.
+const (
+	firstCompactShareDataSize = ShareSize - NamespaceSize - ShareInfoBytes - SequenceLenBytes - reservedBytesSize
+	contCompactShareDataSize  = ShareSize - NamespaceSize - ShareInfoBytes - reservedBytesSize
+)
+
+// CompactShareSplitter packs one or more blobs that share both a namespace
+// and a share version into a stream of fixed-size shares. Unlike the
+// SparseShareSplitter, which always starts a new share for each blob, it
+// writes a varint length delimiter ahead of each blob's data and lets
+// blobs share the remainder of a share, which saves space when many small
+// blobs are posted under the same namespace. Every share's reserved bytes
+// point at the first new blob beginning in that share, and the first
+// share of the whole sequence additionally carries the sequence's total
+// length; because of this, IsSequenceStart is only set on the first share
+// of the sequence, not on the first share of each individual blob.
+type CompactShareSplitter struct {
+	namespace    Namespace
+	shareVersion uint8
+
+	data       []byte // concatenated framed blob data for every blob written
+	blobStarts []int  // offsets into data where each blob's framing begins
+}
+
+// NewCompactShareSplitter creates a CompactShareSplitter that packs blobs
+// under ns using shareVersion.
+func NewCompactShareSplitter(ns Namespace, shareVersion uint8) *CompactShareSplitter {
+	return &CompactShareSplitter{
+		namespace:    ns,
+		shareVersion: shareVersion,
+	}
+}
+
+// WriteBlob appends blob's framed data (a varint length delimiter,
+// followed by the share-version-1 signer if present, followed by the
+// blob's data) to the share stream.
+func (css *CompactShareSplitter) WriteBlob(blob *Blob) error {
+	if blob.Namespace().Compare(css.namespace) != 0 {
+		return fmt.Errorf("blob namespace %s does not match splitter namespace %s", blob.Namespace(), css.namespace)
+	}
+	if blob.ShareVersion() != css.shareVersion {
+		return fmt.Errorf("blob share version %d does not match splitter share version %d", blob.ShareVersion(), css.shareVersion)
+	}
+
+	delimiter := make([]byte, binary.MaxVarintLen64)
+	n := binary.PutUvarint(delimiter, uint64(blob.DataLen()))
+
+	css.blobStarts = append(css.blobStarts, len(css.data))
+	css.data = append(css.data, delimiter[:n]...)
+	if blob.ShareVersion() == ShareVersionOne {
+		css.data = append(css.data, blob.Signer()...)
+	}
+	css.data = append(css.data, blob.Data()...)
+	return nil
+}
+
+// Export chunks the accumulated, framed blob data into fixed-size shares,
+// computing each share's reserved bytes and, for the first share, the
+// sequence length, and returns the full stream of shares written so far.
+func (css *CompactShareSplitter) Export() ([]Share, error) {
+	if len(css.data) == 0 {
+		return nil, nil
+	}
+
+	var shares []Share
+	cursor := 0
+	for cursor < len(css.data) {
+		isFirst := len(shares) == 0
+		capacity := contCompactShareDataSize
+		if isFirst {
+			capacity = firstCompactShareDataSize
+		}
+		end := cursor + capacity
+		if end > len(css.data) {
+			end = len(css.data)
+		}
+		chunk := css.data[cursor:end]
+
+		// reserved records one-indexed the offset, within this share's
+		// chunk, of the first blob that begins in it, or 0 if none does.
+		var reserved uint32
+		for _, start := range css.blobStarts {
+			if start >= cursor && start < end {
+				reserved = uint32(start-cursor) + 1
+				break
+			}
+		}
+
+		infoByte, err := NewInfoByte(css.shareVersion, isFirst)
+		if err != nil {
+			return nil, err
+		}
+
+		raw := make([]byte, 0, ShareSize)
+		raw = append(raw, css.namespace.Bytes()...)
+		raw = append(raw, byte(infoByte))
+		if isFirst {
+			seqLen := make([]byte, SequenceLenBytes)
+			binary.BigEndian.PutUint32(seqLen, uint32(len(css.data)))
+			raw = append(raw, seqLen...)
+		}
+		reservedBytes := make([]byte, reservedBytesSize)
+		binary.BigEndian.PutUint32(reservedBytes, reserved)
+		raw = append(raw, reservedBytes...)
+		raw = append(raw, chunk...)
+		for len(raw) < ShareSize {
+			raw = append(raw, 0)
+		}
+
+		share, err := NewShare(raw)
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, *share)
+
+		cursor = end
+	}
+
+	return shares, nil
+}
+
+// Count returns the number of shares that Export would currently produce
+// from the blob data written so far.
+func (css *CompactShareSplitter) Count() int {
+	if len(css.data) == 0 {
+		return 0
+	}
+	remaining := len(css.data) - firstCompactShareDataSize
+	if remaining <= 0 {
+		return 1
+	}
+	return 1 + (remaining+contCompactShareDataSize-1)/contCompactShareDataSize
+}
+
+// PackOption configures the behavior of PackBlobs.
+type PackOption func(*packConfig)
+
+type packConfig struct {
+	sort bool
+}
+
+// WithoutSort disables PackBlobs' default behavior of sorting blobs by
+// namespace before packing them. Use this when the caller has already
+// established the desired share ordering and wants PackBlobs to preserve
+// it exactly.
+func WithoutSort() PackOption {
+	return func(cfg *packConfig) {
+		cfg.sort = false
+	}
+}
+
+// PackBlobs packs blobs into a compact stream of shares. Adjacent blobs
+// that share both a namespace and a share version are written to the same
+// CompactShareSplitter so that small blobs can share space within a
+// share; blobs are sorted by namespace first (see SortBlobs) so that
+// compaction isn't limited to blobs that already happen to be adjacent,
+// unless WithoutSort is passed.
+func PackBlobs(blobs []*Blob, opts ...PackOption) ([]Share, error) {
+	cfg := &packConfig{sort: true}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	ordered := blobs
+	if cfg.sort {
+		ordered = make([]*Blob, len(blobs))
+		copy(ordered, blobs)
+		SortBlobs(ordered)
+	}
+
+	var shares []Share
+	for i := 0; i < len(ordered); {
+		ns := ordered[i].Namespace()
+		sv := ordered[i].ShareVersion()
+		splitter := NewCompactShareSplitter(ns, sv)
+
+		j := i
+		for j < len(ordered) && ordered[j].Namespace().Compare(ns) == 0 && ordered[j].ShareVersion() == sv {
+			if err := splitter.WriteBlob(ordered[j]); err != nil {
+				return nil, err
+			}
+			j++
+		}
+
+		groupShares, err := splitter.Export()
+		if err != nil {
+			return nil, err
+		}
+		shares = append(shares, groupShares...)
+		i = j
+	}
+
+	return shares, nil
+}
+
+// ParseCompactShares is the inverse of PackBlobs: it reconstructs the
+// blobs packed into shares by CompactShareSplitter.
+func ParseCompactShares(shares []Share) ([]*Blob, error) {
+	var blobs []*Blob
+
+	i := 0
+	for i < len(shares) {
+		ns, err := shares[i].Namespace()
+		if err != nil {
+			return nil, err
+		}
+		infoByte, err := shares[i].InfoByte()
+		if err != nil {
+			return nil, err
+		}
+		if !infoByte.IsSequenceStart() {
+			return nil, fmt.Errorf("expected a sequence-start share at index %d", i)
+		}
+		shareVersion := infoByte.Version()
+
+		raw := shares[i].ToBytes()
+		cursor := NamespaceSize + ShareInfoBytes
+		if len(raw) < cursor+SequenceLenBytes+reservedBytesSize {
+			return nil, fmt.Errorf("share at index %d is too short to contain a sequence header", i)
+		}
+		seqLen := binary.BigEndian.Uint32(raw[cursor : cursor+SequenceLenBytes])
+		cursor += SequenceLenBytes
+
+		// claimedOffsets collects, in order, the blob-start offsets (within
+		// the group's reassembled data) that each share's reserved bytes
+		// claim. They're checked against the offsets parseCompactGroup
+		// actually finds once the group's data has been reassembled, so a
+		// corrupted reserved-bytes field doesn't go unnoticed.
+		var claimedOffsets []int
+		if firstReserved := binary.BigEndian.Uint32(raw[cursor : cursor+reservedBytesSize]); firstReserved != 0 {
+			claimedOffsets = append(claimedOffsets, int(firstReserved-1))
+		}
+		cursor += reservedBytesSize
+
+		data := append([]byte{}, raw[cursor:]...)
+
+		j := i + 1
+		for uint32(len(data)) < seqLen && j < len(shares) {
+			nraw := shares[j].ToBytes()
+			ncursor := NamespaceSize + ShareInfoBytes
+			if len(nraw) < ncursor+reservedBytesSize {
+				return nil, fmt.Errorf("share at index %d is too short to contain reserved bytes", j)
+			}
+			if reserved := binary.BigEndian.Uint32(nraw[ncursor : ncursor+reservedBytesSize]); reserved != 0 {
+				claimedOffsets = append(claimedOffsets, len(data)+int(reserved-1))
+			}
+			ncursor += reservedBytesSize
+			data = append(data, nraw[ncursor:]...)
+			j++
+		}
+		if uint32(len(data)) < seqLen {
+			return nil, fmt.Errorf("truncated compact share sequence: expected %d bytes, got %d", seqLen, len(data))
+		}
+		data = data[:seqLen]
+
+		// an offset claimed by a share's reserved bytes can point past the
+		// sequence's true length if that share is entirely padding; such
+		// offsets don't correspond to any blob once data is truncated to
+		// seqLen, so they're dropped rather than treated as corruption.
+		kept := claimedOffsets[:0]
+		for _, off := range claimedOffsets {
+			if off < len(data) {
+				kept = append(kept, off)
+			}
+		}
+		claimedOffsets = kept
+
+		groupBlobs, actualOffsets, err := parseCompactGroup(ns, shareVersion, data)
+		if err != nil {
+			return nil, err
+		}
+		if err := verifyReservedOffsets(claimedOffsets, actualOffsets); err != nil {
+			return nil, fmt.Errorf("group starting at share %d: %w", i, err)
+		}
+		blobs = append(blobs, groupBlobs...)
+		i = j
+	}
+
+	return blobs, nil
+}
+
+// verifyReservedOffsets checks that the blob-start offsets claimed by a
+// compact share group's reserved bytes (claimed) agree with the offsets
+// actually found while reassembling the group's framed blob data (actual).
+func verifyReservedOffsets(claimed, actual []int) error {
+	if len(claimed) != len(actual) {
+		return fmt.Errorf("reserved bytes claim %d blob starts, found %d", len(claimed), len(actual))
+	}
+	for i := range claimed {
+		if claimed[i] != actual[i] {
+			return fmt.Errorf("blob %d: reserved bytes claim offset %d, found %d", i, claimed[i], actual[i])
+		}
+	}
+	return nil
+}
+
+// parseCompactGroup reconstructs the blobs framed into a single
+// CompactShareSplitter's data stream, along with each blob's framing
+// offset within data, for cross-checking against the group's reserved
+// bytes.
+func parseCompactGroup(ns Namespace, shareVersion uint8, data []byte) ([]*Blob, []int, error) {
+	var blobs []*Blob
+	var starts []int
+	offset := 0
+	for len(data) > 0 {
+		starts = append(starts, offset)
+
+		length, n := binary.Uvarint(data)
+		if n <= 0 {
+			return nil, nil, errors.New("invalid length delimiter while parsing compact shares")
+		}
+		data = data[n:]
+		offset += n
+
+		var signer []byte
+		if shareVersion == ShareVersionOne {
+			if uint64(len(data)) < SignerSize {
+				return nil, nil, errors.New("truncated signer while parsing compact shares")
+			}
+			signer = data[:SignerSize]
+			data = data[SignerSize:]
+			offset += SignerSize
+		}
+
+		if uint64(len(data)) < length {
+			return nil, nil, errors.New("truncated blob data while parsing compact shares")
+		}
+		blobData := data[:length]
+		data = data[length:]
+		offset += int(length)
+
+		blob, err := NewBlob(ns, blobData, shareVersion, signer)
+		if err != nil {
+			return nil, nil, err
+		}
+		blobs = append(blobs, blob)
+	}
+
+	return blobs, starts, nil
+}
+
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
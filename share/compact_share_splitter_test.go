@@ -0,0 +1,153 @@
+package share
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestCompactShareSplitterRoundTrip(t *testing.T) {
+	ns := namespaceWithID(t, 7)
+
+	blobs := []*Blob{}
+	for i := 0; i < 3; i++ {
+		data := bytes.Repeat([]byte{byte(i + 1)}, 50)
+		blob, err := NewV0Blob(ns, data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		blobs = append(blobs, blob)
+	}
+
+	splitter := NewCompactShareSplitter(ns, ShareVersionZero)
+	for _, blob := range blobs {
+		if err := splitter.WriteBlob(blob); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got, want := splitter.Count(), 1; got != want {
+		t.Fatalf("Count() = %d, want %d", got, want)
+	}
+
+	shares, err := splitter.Export()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := ParseCompactShares(shares)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != len(blobs) {
+		t.Fatalf("expected %d blobs, got %d", len(blobs), len(got))
+	}
+	for i, blob := range blobs {
+		if !bytes.Equal(got[i].Data(), blob.Data()) {
+			t.Fatalf("blob %d: expected data %x, got %x", i, blob.Data(), got[i].Data())
+		}
+	}
+}
+
+func TestCompactShareSplitterRoundTripSpansMultipleShares(t *testing.T) {
+	ns := namespaceWithID(t, 8)
+
+	big, err := NewV0Blob(ns, bytes.Repeat([]byte{0xCD}, 2*ShareSize))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	small, err := NewV0Blob(ns, []byte("tiny"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	shares, err := PackBlobs([]*Blob{big, small})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(shares) < 2 {
+		t.Fatalf("expected the blobs to span multiple shares, got %d", len(shares))
+	}
+
+	got, err := ParseCompactShares(shares)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 blobs, got %d", len(got))
+	}
+	if !bytes.Equal(got[0].Data(), big.Data()) {
+		t.Fatal("expected the large blob's data to round trip unchanged")
+	}
+	if !bytes.Equal(got[1].Data(), small.Data()) {
+		t.Fatal("expected the small blob's data to round trip unchanged")
+	}
+}
+
+func TestParseCompactSharesDetectsCorruptedReservedBytes(t *testing.T) {
+	ns := namespaceWithID(t, 14)
+
+	big, err := NewV0Blob(ns, bytes.Repeat([]byte{0xEF}, 2*ShareSize))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	small, err := NewV0Blob(ns, []byte("tiny"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	shares, err := PackBlobs([]*Blob{big, small})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(shares) < 2 {
+		t.Fatalf("expected the blobs to span multiple shares, got %d", len(shares))
+	}
+
+	// corrupt the last share's reserved bytes so they no longer agree with
+	// where the small blob actually starts.
+	last := len(shares) - 1
+	raw := append([]byte{}, shares[last].ToBytes()...)
+	reservedOffset := NamespaceSize + ShareInfoBytes
+	raw[reservedOffset+reservedBytesSize-1]++
+
+	corrupted, err := NewShare(raw)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shares[last] = *corrupted
+
+	if _, err := ParseCompactShares(shares); err == nil {
+		t.Fatal("expected an error when a share's reserved bytes don't match the blob's actual start")
+	}
+}
+
+func TestPackBlobsGroupsByNamespaceAndShareVersion(t *testing.T) {
+	nsA := namespaceWithID(t, 10)
+	nsB := namespaceWithID(t, 11)
+	signer := bytes.Repeat([]byte{3}, SignerSize)
+
+	a, err := NewV0Blob(nsA, []byte("a"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := NewV1Blob(nsA, []byte("b"), signer)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c, err := NewV0Blob(nsB, []byte("c"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	shares, err := PackBlobs([]*Blob{a, b, c})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := ParseCompactShares(shares)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 blobs, got %d", len(got))
+	}
+}
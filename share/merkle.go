@@ -0,0 +1,64 @@
+package share
+
+import "crypto/sha256"
+
+// leafPrefix and innerPrefix are the domain separation prefixes used to
+// distinguish leaf and inner node hashes in the plain (non-namespaced)
+// Merkle tree, mirroring the convention used by tendermint's simple Merkle
+// tree (RFC 6962 style domain separation).
+var (
+	leafPrefix  = []byte{0}
+	innerPrefix = []byte{1}
+)
+
+// merkleRoot computes the root of a plain binary Merkle tree over items,
+// following tendermint's simple Merkle tree algorithm: a single item hashes
+// to a leaf hash, and more than one item is split at the largest power of
+// two strictly smaller than len(items), with the two halves hashed
+// recursively and combined with an inner node hash.
+func merkleRoot(items [][]byte) []byte {
+	switch len(items) {
+	case 0:
+		return sha256.New().Sum(nil)
+	case 1:
+		return leafHash(items[0])
+	default:
+		k := splitPoint(len(items))
+		left := merkleRoot(items[:k])
+		right := merkleRoot(items[k:])
+		return innerHash(left, right)
+	}
+}
+
+func leafHash(leaf []byte) []byte {
+	h := sha256.New()
+	h.Write(leafPrefix)
+	h.Write(leaf)
+	return h.Sum(nil)
+}
+
+func innerHash(left, right []byte) []byte {
+	h := sha256.New()
+	h.Write(innerPrefix)
+	h.Write(left)
+	h.Write(right)
+	return h.Sum(nil)
+}
+
+// splitPoint returns the largest power of two that is strictly smaller than
+// length, used to split a slice of leaves into balanced left and right
+// subtrees.
+func splitPoint(length int) int {
+	if length < 1 {
+		panic("trying to split a tree with size < 1")
+	}
+	k := 1
+	for k < length {
+		k <<= 1
+	}
+	k >>= 1
+	if k == length {
+		k >>= 1
+	}
+	return k
+}
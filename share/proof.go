@@ -0,0 +1,189 @@
+package share
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/celestiaorg/nmt"
+)
+
+// Proof is a proof that a blob's shares are included in the data square
+// committed to by a data root. It carries one NMT proof per row that the
+// blob's shares span, alongside the row and column roots of the data
+// availability header needed to recompute the data root.
+//
+// This is an intentional divergence from a bare []*nmt.Proof: Verify has
+// to recompute the data root to check a proof against, which needs the
+// row and column roots and the blob's starting row alongside the per-row
+// NMT proofs, so a bare slice of NMT proofs can't be self-verifying.
+type Proof struct {
+	// RowProofs are the NMT inclusion proofs of the blob's shares into the
+	// rows they span, in row order.
+	RowProofs []*nmt.Proof
+	// RowRoots are all of the row roots of the data availability header
+	// the blob was included in.
+	RowRoots [][]byte
+	// ColumnRoots are all of the column roots of the data availability
+	// header the blob was included in.
+	ColumnRoots [][]byte
+	// StartRow is the index, within RowRoots, of the first row the blob's
+	// shares fall in.
+	StartRow int
+}
+
+// NewProof builds an inclusion Proof for blob, starting at startRow. rows
+// holds, for each row the blob's shares span (in row order, starting at
+// startRow), the row's full contents as laid out in the square: the
+// blob's own shares plus whatever other namespaces' and parity shares
+// share that row. This matters because a real data availability header's
+// row root is the NMT root over the whole row, not just a single blob's
+// shares, so the proof must be built from a tree containing the row's
+// actual contents in order to later verify against that root.
+func NewProof(blob *Blob, startRow int, rows [][]Share, rowRoots, columnRoots [][]byte) (Proof, error) {
+	if len(rows) == 0 {
+		return Proof{}, errors.New("at least one row is required")
+	}
+	if startRow+len(rows) > len(rowRoots) {
+		return Proof{}, fmt.Errorf("blob spans rows [%d, %d) but only %d row roots were provided", startRow, startRow+len(rows), len(rowRoots))
+	}
+
+	nID := blob.Namespace().Bytes()
+	rowProofs := make([]*nmt.Proof, len(rows))
+	for i, rowShares := range rows {
+		tree := nmt.New(sha256.New, nmt.NamespaceIDSize(NamespaceSize))
+		for _, sh := range rowShares {
+			if err := tree.Push(sh.ToBytes()); err != nil {
+				return Proof{}, fmt.Errorf("building nmt for row %d: %w", startRow+i, err)
+			}
+		}
+		root, err := tree.Root()
+		if err != nil {
+			return Proof{}, fmt.Errorf("computing root for row %d: %w", startRow+i, err)
+		}
+		if !bytes.Equal(root, rowRoots[startRow+i]) {
+			return Proof{}, fmt.Errorf("row %d: computed root does not match the provided row root", startRow+i)
+		}
+		proof, err := tree.ProveNamespace(nID)
+		if err != nil {
+			return Proof{}, fmt.Errorf("proving namespace for row %d: %w", startRow+i, err)
+		}
+		rowProofs[i] = &proof
+	}
+
+	return Proof{
+		RowProofs:   rowProofs,
+		RowRoots:    rowRoots,
+		ColumnRoots: columnRoots,
+		StartRow:    startRow,
+	}, nil
+}
+
+// Verify returns true if proof proves that b's shares were included in the
+// square committed to by dataRoot. Each row proof is checked against the
+// blob's own shares that fall in that row - the number of which is read
+// off the NMT proof's own leaf range - rather than assuming the blob's
+// shares fill the row, so a blob sharing a row with other namespaces'
+// shares still verifies correctly.
+func (b *Blob) Verify(dataRoot []byte, proof Proof) (bool, error) {
+	shares, err := b.ToShares()
+	if err != nil {
+		return false, err
+	}
+	if len(shares) == 0 {
+		return false, errors.New("blob has no shares")
+	}
+	if len(proof.RowProofs) == 0 {
+		return false, errors.New("proof has no row proofs")
+	}
+	if proof.StartRow+len(proof.RowProofs) > len(proof.RowRoots) {
+		return false, fmt.Errorf("proof spans rows [%d, %d) but only has %d row roots", proof.StartRow, proof.StartRow+len(proof.RowProofs), len(proof.RowRoots))
+	}
+
+	nID := b.Namespace().Bytes()
+	cursor := 0
+	for i, rowProof := range proof.RowProofs {
+		count := rowProof.End() - rowProof.Start()
+		if count <= 0 || cursor+count > len(shares) {
+			return false, fmt.Errorf("row proof %d claims %d leaves, but only %d of the blob's shares remain", i, count, len(shares)-cursor)
+		}
+		rowShares := shares[cursor : cursor+count]
+		leaves := make([][]byte, len(rowShares))
+		for j, sh := range rowShares {
+			leaves[j] = sh.ToBytes()
+		}
+		root := proof.RowRoots[proof.StartRow+i]
+		if !rowProof.VerifyNamespace(sha256.New, nID, leaves, root) {
+			return false, nil
+		}
+		cursor += count
+	}
+	if cursor != len(shares) {
+		return false, errors.New("proof does not cover all of the blob's shares")
+	}
+
+	items := make([][]byte, 0, len(proof.RowRoots)+len(proof.ColumnRoots))
+	items = append(items, proof.RowRoots...)
+	items = append(items, proof.ColumnRoots...)
+	computedRoot := merkleRoot(items)
+
+	return bytes.Equal(computedRoot, dataRoot), nil
+}
+
+// jsonProof is the wire representation of a single row's nmt.Proof, since
+// nmt.Proof does not expose its fields directly for JSON encoding.
+type jsonProof struct {
+	Start    int      `json:"start"`
+	End      int      `json:"end"`
+	Nodes    [][]byte `json:"nodes"`
+	LeafHash []byte   `json:"leaf_hash,omitempty"`
+}
+
+type jsonProofEnvelope struct {
+	RowProofs   []jsonProof `json:"row_proofs"`
+	RowRoots    [][]byte    `json:"row_roots"`
+	ColumnRoots [][]byte    `json:"column_roots"`
+	StartRow    int         `json:"start_row"`
+}
+
+// MarshalJSON converts the proof to JSON, following the shape celestia-node
+// uses to transport blob inclusion proofs over RPC.
+func (p Proof) MarshalJSON() ([]byte, error) {
+	envelope := jsonProofEnvelope{
+		RowProofs:   make([]jsonProof, len(p.RowProofs)),
+		RowRoots:    p.RowRoots,
+		ColumnRoots: p.ColumnRoots,
+		StartRow:    p.StartRow,
+	}
+	for i, rp := range p.RowProofs {
+		envelope.RowProofs[i] = jsonProof{
+			Start:    rp.Start(),
+			End:      rp.End(),
+			Nodes:    rp.Nodes(),
+			LeafHash: rp.LeafHash(),
+		}
+	}
+	return json.Marshal(envelope)
+}
+
+// UnmarshalJSON converts JSON encoded bytes back into a Proof.
+func (p *Proof) UnmarshalJSON(data []byte) error {
+	var envelope jsonProofEnvelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return err
+	}
+
+	rowProofs := make([]*nmt.Proof, len(envelope.RowProofs))
+	for i, rp := range envelope.RowProofs {
+		proof := nmt.NewInclusionProof(rp.Start, rp.End, rp.Nodes, true)
+		rowProofs[i] = &proof
+	}
+
+	p.RowProofs = rowProofs
+	p.RowRoots = envelope.RowRoots
+	p.ColumnRoots = envelope.ColumnRoots
+	p.StartRow = envelope.StartRow
+	return nil
+}
@@ -0,0 +1,100 @@
+package share
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/celestiaorg/nmt"
+)
+
+// buildRowRoot computes the NMT root over a full row's shares, the same
+// way a real data availability header would, so that tests exercise
+// NewProof/Verify against a realistic root rather than one derived only
+// from a single blob's shares.
+func buildRowRoot(t *testing.T, row []Share) []byte {
+	t.Helper()
+	tree := nmt.New(sha256.New, nmt.NamespaceIDSize(NamespaceSize))
+	for _, sh := range row {
+		if err := tree.Push(sh.ToBytes()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	root, err := tree.Root()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return root
+}
+
+func TestProofRoundTrip(t *testing.T) {
+	otherNS := namespaceWithID(t, 2)
+	blobNS := namespaceWithID(t, 5)
+
+	otherBlob, err := NewV0Blob(otherNS, []byte("someone else's data"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	otherShares, err := otherBlob.ToShares()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	blob, err := NewV0Blob(blobNS, []byte("my blob's data"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	blobShares, err := blob.ToShares()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// the row contains both namespaces, ordered as an NMT requires, so the
+	// blob does not occupy the whole row.
+	row := append(append([]Share{}, otherShares...), blobShares...)
+	rowRoot := buildRowRoot(t, row)
+	rowRoots := [][]byte{rowRoot}
+	columnRoots := [][]byte{{1, 2, 3, 4}}
+
+	dataRoot := merkleRoot(append(append([][]byte{}, rowRoots...), columnRoots...))
+
+	proof, err := NewProof(blob, 0, [][]Share{row}, rowRoots, columnRoots)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ok, err := blob.Verify(dataRoot, proof)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !ok {
+		t.Fatal("expected proof to verify against the real data root")
+	}
+
+	corrupted := append([]byte{}, dataRoot...)
+	corrupted[0] ^= 0xFF
+	ok, err = blob.Verify(corrupted, proof)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Fatal("expected proof to fail to verify against a corrupted data root")
+	}
+}
+
+func TestNewProofRowRootMismatch(t *testing.T) {
+	ns := namespaceWithID(t, 1)
+	blob, err := NewV0Blob(ns, []byte("data"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	shares, err := blob.ToShares()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	wrongRowRoots := [][]byte{bytes.Repeat([]byte{0xAA}, 32)}
+	if _, err := NewProof(blob, 0, [][]Share{shares}, wrongRowRoots, nil); err == nil {
+		t.Fatal("expected an error when the provided row root doesn't match the row's actual contents")
+	}
+}
@@ -0,0 +1,99 @@
+package share
+
+import "encoding/binary"
+
+// SparseShareSplitter splits a single blob's data into one or more
+// fixed-size shares, always starting a new share for the blob's sequence
+// (unlike the CompactShareSplitter, which can pack several blobs into the
+// same share). The first share of the sequence carries the blob's
+// namespace, info byte, sequence length and, depending on share version, a
+// signer and/or content type tag, ahead of the blob's data; subsequent
+// shares carry only namespace, info byte, and data.
+type SparseShareSplitter struct {
+	shares []Share
+}
+
+// NewSparseShareSplitter creates an empty SparseShareSplitter.
+func NewSparseShareSplitter() *SparseShareSplitter {
+	return &SparseShareSplitter{}
+}
+
+// sparseShareDataCap is the number of bytes of a share's fixed ShareSize
+// bytes left over for the sequence header and blob data once the
+// namespace and info byte have been written; the remainder, including the
+// sequence length and any share-version-specific header fields, is
+// prepended to the blob's data before being chunked across shares.
+const sparseShareDataCap = ShareSize - NamespaceSize - ShareInfoBytes
+
+// Write splits blob into shares and appends them to the splitter's share
+// stream.
+func (sss *SparseShareSplitter) Write(blob *Blob) error {
+	header, err := sequenceHeader(blob)
+	if err != nil {
+		return err
+	}
+
+	raw := append(header, blob.Data()...)
+
+	for i := 0; len(raw) > 0 || i == 0; i++ {
+		n := sparseShareDataCap
+		if n > len(raw) {
+			n = len(raw)
+		}
+		chunk := raw[:n]
+		raw = raw[n:]
+
+		infoByte, err := NewInfoByte(blob.ShareVersion(), i == 0)
+		if err != nil {
+			return err
+		}
+
+		data := make([]byte, 0, ShareSize)
+		data = append(data, blob.Namespace().Bytes()...)
+		data = append(data, byte(infoByte))
+		data = append(data, chunk...)
+		for len(data) < ShareSize {
+			data = append(data, 0)
+		}
+
+		share, err := NewShare(data)
+		if err != nil {
+			return err
+		}
+		sss.shares = append(sss.shares, *share)
+	}
+
+	return nil
+}
+
+// sequenceHeader builds the bytes that precede a blob's data in the first
+// share of its sequence: a fixed-width sequence length, followed by the
+// share-version-specific fields (a mandatory signer for share version 1,
+// or a content type tag and a signer slot, zero-filled when absent, for
+// share version 2).
+func sequenceHeader(blob *Blob) ([]byte, error) {
+	header := make([]byte, SequenceLenBytes)
+	binary.BigEndian.PutUint32(header, uint32(blob.DataLen()))
+
+	switch blob.ShareVersion() {
+	case ShareVersionOne:
+		header = append(header, blob.Signer()...)
+	case ShareVersionTwo:
+		header = append(header, blob.ContentType())
+		signer := make([]byte, SignerSize)
+		copy(signer, blob.Signer())
+		header = append(header, signer...)
+	}
+
+	return header, nil
+}
+
+// Export returns the full stream of shares written so far.
+func (sss *SparseShareSplitter) Export() []Share {
+	return sss.shares
+}
+
+// Count returns the number of shares written so far.
+func (sss *SparseShareSplitter) Count() int {
+	return len(sss.shares)
+}